@@ -0,0 +1,137 @@
+package moq
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestMockCallLogRecordsAndAssertsGenericCalls mocks Store (a generic
+// interface) with WithCallLog(true), writes the result into a throwaway
+// module alongside the runtime package and testdata/generics, and runs
+// a small program against it that drives the mock and exercises
+// AssertCalled, AssertCalledInOrder and AssertNumberOfCalls. Unlike
+// assertCompiles, this checks the call log and its assertion helpers
+// actually behave correctly at runtime, not just that the generated code
+// type checks.
+func TestMockCallLogRecordsAndAssertsGenericCalls(t *testing.T) {
+	m, err := New("testdata/generics", "generics", WithCallLog(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := m.Mock(&buf, "Store"); err != nil {
+		t.Fatal(err)
+	}
+
+	modRoot := t.TempDir()
+	writeFile(t, filepath.Join(modRoot, "go.mod"), "module github.com/mkfsn/moq\n\ngo 1.21\n")
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	copyFile(t, filepath.Join(wd, "runtime", "runtime.go"), filepath.Join(modRoot, "pkg", "moq", "runtime", "runtime.go"))
+	copyFile(t, filepath.Join(wd, "runtime", "controller.go"), filepath.Join(modRoot, "pkg", "moq", "runtime", "controller.go"))
+	copyFile(t, filepath.Join(wd, "runtime", "assertions.go"), filepath.Join(modRoot, "pkg", "moq", "runtime", "assertions.go"))
+	copyFile(t, filepath.Join(wd, "testdata", "generics", "generics.go"), filepath.Join(modRoot, "generics", "generics.go"))
+	writeFile(t, filepath.Join(modRoot, "generics", "store_mock.go"), buf.String())
+	writeFile(t, filepath.Join(modRoot, "cmd", "main.go"), callLogDriverSource)
+
+	cmd := exec.Command("go", "run", "./cmd")
+	cmd.Dir = modRoot
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("driver program failed: %v\nstdout:\n%s\nstderr:\n%s", err, out.String(), stderr.String())
+	}
+	if out.String() != "ok\n" {
+		t.Fatalf("driver program reported a failed assertion:\n%s", out.String())
+	}
+}
+
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func copyFile(t *testing.T, src, dst string) {
+	t.Helper()
+	b, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dst, string(b))
+}
+
+// callLogDriverSource drives StoreMock's call log and its AssertCalled,
+// AssertCalledInOrder and AssertNumberOfCalls helpers, reporting "ok" on
+// stdout only if every assertion passes. It's a real program rather than
+// a test file because it needs to run in its own module, built against
+// the runtime package and testdata/generics copied alongside it.
+const callLogDriverSource = `package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mkfsn/moq/generics"
+	moqruntime "github.com/mkfsn/moq/pkg/moq/runtime"
+)
+
+type reporter struct{ failed []string }
+
+func (r *reporter) Errorf(format string, args ...interface{}) {
+	r.failed = append(r.failed, fmt.Sprintf(format, args...))
+}
+func (r *reporter) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+}
+
+func main() {
+	mock := &generics.StoreMock[string, int, float64]{
+		SetFunc: func(key string, val int) {},
+		GetFunc: func(key string) (int, bool) { return 0, false },
+		SumFunc: func(nums ...float64) float64 { return 0 },
+	}
+
+	mock.Set("a", 1)
+	mock.Get("a")
+	mock.Set("b", 2)
+
+	r := &reporter{}
+	mock.AssertCalled(r, "Set", moqruntime.Eq("a"), moqruntime.Eq(1))
+	mock.AssertCalled(r, "Get", moqruntime.Eq("a"))
+	mock.AssertNumberOfCalls(r, "Set", 2)
+	mock.AssertNumberOfCalls(r, "Get", 1)
+	mock.AssertCalledInOrder(r,
+		generics.StoreMockSetCall[string, int, float64]{Key: "a", Val: 1},
+		generics.StoreMockGetCall[string, int, float64]{Key: "a"},
+		generics.StoreMockSetCall[string, int, float64]{Key: "b", Val: 2},
+	)
+
+	// Set was never called with "z", so this must fail - confirms
+	// AssertCalled isn't trivially satisfied.
+	neg := &reporter{}
+	mock.AssertCalled(neg, "Set", moqruntime.Eq("z"), moqruntime.Eq(1))
+	if len(neg.failed) == 0 {
+		r.failed = append(r.failed, "AssertCalled did not fail for an argument combination that was never called")
+	}
+
+	if len(r.failed) != 0 {
+		for _, f := range r.failed {
+			fmt.Println(f)
+		}
+		os.Exit(1)
+	}
+	fmt.Println("ok")
+}
+`
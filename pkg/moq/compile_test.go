@@ -0,0 +1,118 @@
+package moq
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// assertCompiles parses and type-checks generated, failing t if it
+// doesn't compile. It resolves imports of this module (including the
+// runtime package generated mocks depend on, and any local testdata
+// package an embedded interface might pull in) against their source on
+// disk, so it works without a go.mod. Everything else is resolved
+// through the standard library source importer. This exists so tests
+// assert a generated mock actually builds instead of just matching
+// strings in its source - a generated mock can look right and still
+// fail to compile, e.g. from an unused import or a mismatched return
+// arity.
+//
+// companionDirs lists directories whose non-test .go files are parsed
+// and type-checked alongside generated, in the same package - pass the
+// source directory Mock read the interface from when generated shares
+// its package rather than importing it, the way Mock (unlike Generate)
+// always does.
+func assertCompiles(t *testing.T, generated []byte, companionDirs ...string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	moduleRoot := filepath.Join(wd, "..", "..")
+	imp := &localModuleImporter{
+		fset:     token.NewFileSet(),
+		prefix:   "github.com/mkfsn/moq/",
+		root:     moduleRoot,
+		fallback: importer.ForCompiler(token.NewFileSet(), "source", nil),
+		resolved: make(map[string]*types.Package),
+	}
+	file, err := parser.ParseFile(imp.fset, "generated_mock.go", generated, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("generated mock doesn't parse: %v\n%s", err, generated)
+	}
+	files := []*ast.File{file}
+	noTestFiles := func(i os.FileInfo) bool {
+		return !strings.HasSuffix(i.Name(), "_test.go")
+	}
+	for _, dir := range companionDirs {
+		astPkgs, err := parser.ParseDir(imp.fset, dir, noTestFiles, parser.SpuriousErrors)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, astPkg := range astPkgs {
+			if strings.Contains(name, "_test") {
+				continue
+			}
+			for _, f := range astPkg.Files {
+				files = append(files, f)
+			}
+		}
+	}
+	conf := types.Config{Importer: imp}
+	if _, err := conf.Check(file.Name.Name, imp.fset, files, nil); err != nil {
+		t.Fatalf("generated mock doesn't compile: %v\n%s", err, generated)
+	}
+}
+
+// localModuleImporter resolves import paths under prefix against their
+// source on disk, rooted at root, and everything else through fallback.
+// It lets assertCompiles type-check a generated mock's imports of this
+// module's own packages without a go.mod.
+type localModuleImporter struct {
+	fset     *token.FileSet
+	prefix   string
+	root     string
+	fallback types.Importer
+	resolved map[string]*types.Package
+}
+
+func (imp *localModuleImporter) Import(path string) (*types.Package, error) {
+	if !strings.HasPrefix(path, imp.prefix) {
+		return imp.fallback.Import(path)
+	}
+	if pkg, ok := imp.resolved[path]; ok {
+		return pkg, nil
+	}
+	dir := filepath.Join(imp.root, strings.TrimPrefix(path, imp.prefix))
+	noTestFiles := func(i os.FileInfo) bool {
+		return !strings.HasSuffix(i.Name(), "_test.go")
+	}
+	astPkgs, err := parser.ParseDir(imp.fset, dir, noTestFiles, parser.SpuriousErrors)
+	if err != nil {
+		return nil, err
+	}
+	var files []*ast.File
+	var pkgName string
+	for name, astPkg := range astPkgs {
+		if strings.Contains(name, "_test") {
+			continue
+		}
+		pkgName = name
+		for _, f := range astPkg.Files {
+			files = append(files, f)
+		}
+	}
+	conf := types.Config{Importer: imp}
+	tpkg, err := conf.Check(pkgName, imp.fset, files, nil)
+	if err != nil {
+		return nil, err
+	}
+	imp.resolved[path] = tpkg
+	return tpkg, nil
+}
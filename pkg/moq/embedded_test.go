@@ -0,0 +1,57 @@
+package moq
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestMockEmbeddedInterfaceAcrossPackages mocks outer.Thing, which
+// embeds mid.ReadCloser, which itself embeds base.Reader, mirroring
+// mockgen's aux_imports_embedded_interface case: mocking an interface
+// that transitively embeds interfaces from other packages. It checks
+// the generated mock carries every flattened method and actually
+// compiles - none of Thing's methods reference a type from base or
+// mid, so a prior version of this test that only grepped for those
+// packages' import paths missed that they were imported unused.
+func TestMockEmbeddedInterfaceAcrossPackages(t *testing.T) {
+	m, err := New("testdata/embedded/outer", "outer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := m.Mock(&buf, "Thing"); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	assertCompiles(t, buf.Bytes())
+
+	for _, want := range []string{
+		"func (mock *ThingMock) Read(p []byte) (int, error) {",
+		"func (mock *ThingMock) Close() error {",
+		"func (mock *ThingMock) Do() error {",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("generated mock is missing %q:\n%s", want, out)
+		}
+	}
+}
+
+// TestMockEmbeddedInterfaceSignatureConflict mocks Conflicting, which
+// embeds two interfaces that disagree on the signature of Do. Go
+// itself rejects such a declaration, so this checks Mock surfaces that
+// failure as an error rather than panicking.
+func TestMockEmbeddedInterfaceSignatureConflict(t *testing.T) {
+	m, err := New("testdata/embedded/conflict", "conflict")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	err = m.Mock(&buf, "Conflicting")
+	if err == nil {
+		t.Fatal("Mock succeeded, want an error for conflicting embedded method signatures")
+	}
+	if !strings.Contains(err.Error(), "Do") {
+		t.Errorf("Mock error = %q, want it to mention the conflicting method", err)
+	}
+}
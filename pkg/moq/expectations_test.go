@@ -0,0 +1,23 @@
+package moq
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestMockExpectationsCompilesWithVoidMethod mocks Store, which has a
+// void method (Set has no return values), with WithExpectations(true)
+// enabled, and checks the result actually compiles. The EXPECT path's
+// DoFn call used to return fn(...) unconditionally, which doesn't type
+// check when fn itself has no return values.
+func TestMockExpectationsCompilesWithVoidMethod(t *testing.T) {
+	m, err := New("testdata/generics", "generics", WithExpectations(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := m.Mock(&buf, "Store"); err != nil {
+		t.Fatal(err)
+	}
+	assertCompiles(t, buf.Bytes(), "testdata/generics")
+}
@@ -0,0 +1,116 @@
+package moq
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// autogeneratedMarker is the comment moqTemplate always emits. MockToFile
+// looks for it before overwriting an existing file, so a hand-written
+// file that happens to share a destination path is never clobbered.
+const autogeneratedMarker = "AUTOGENERATED BY MOQ"
+
+// buildConstraintLine matches a //go:build line or a legacy // +build
+// line.
+var buildConstraintLine = regexp.MustCompile(`^//(go:build|\s*\+build)\b`)
+
+// MockToFile generates a mock for the named interfaces and writes it to
+// path. The write is atomic: output is built in full, then renamed into
+// place, so a failure never leaves path with partial content. If path
+// already exists and its contents don't carry the "AUTOGENERATED BY MOQ"
+// marker, MockToFile refuses to overwrite it rather than risk clobbering
+// a hand-written file. Any //go:build or // +build constraint lines
+// found at the top of the source package are carried over to the top of
+// the generated file, so the mock only builds where the interface it
+// mocks does.
+func (m *Mocker) MockToFile(path string, name ...string) error {
+	var buf bytes.Buffer
+	if tags := m.buildTags(); tags != "" {
+		buf.WriteString(tags)
+	}
+	if err := m.Mock(&buf, name...); err != nil {
+		return err
+	}
+	return writeGenerated(path, buf.Bytes())
+}
+
+// writeGenerated atomically writes generated to path: the full contents
+// are written to a temporary file in path's directory first, then renamed
+// into place, so a failure never leaves path with partial content. If
+// path already exists and its contents don't carry the
+// "AUTOGENERATED BY MOQ" marker, writeGenerated refuses to overwrite it
+// rather than risk clobbering a hand-written file.
+func writeGenerated(path string, generated []byte) error {
+	if existing, err := os.ReadFile(path); err == nil {
+		if !bytes.Contains(existing, []byte(autogeneratedMarker)) {
+			return fmt.Errorf("%s already exists and wasn't generated by moq; refusing to overwrite", path)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, ".moq-*.go.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	_, writeErr := tmp.Write(generated)
+	closeErr := tmp.Close()
+	if writeErr != nil {
+		os.Remove(tmpPath)
+		return writeErr
+	}
+	if closeErr != nil {
+		os.Remove(tmpPath)
+		return closeErr
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// buildTags returns the leading //go:build / // +build lines (plus the
+// blank line Go requires after them) from the first non-test source
+// file in m.src that starts with any, or "" if m.src has no source
+// files or none carry a
+// build constraint. It reads the raw file text rather than the AST,
+// since New parses its package without retaining comments.
+func (m *Mocker) buildTags() string {
+	entries, err := os.ReadDir(m.src)
+	if err != nil {
+		return ""
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(m.src, name))
+		if err != nil {
+			continue
+		}
+		var tags []string
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimRight(line, "\r")
+			if line == "" {
+				continue
+			}
+			if buildConstraintLine.MatchString(line) {
+				tags = append(tags, line)
+				continue
+			}
+			break
+		}
+		if len(tags) > 0 {
+			return strings.Join(tags, "\n") + "\n\n"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,71 @@
+package moq
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestMockToFileWritesCompilableOutputAndPreservesBuildTags mocks Pinger,
+// whose source file carries a //go:build constraint, and checks that
+// MockToFile writes a file that actually compiles and that carries the
+// same build constraint forward.
+func TestMockToFileWritesCompilableOutputAndPreservesBuildTags(t *testing.T) {
+	m, err := New("testdata/buildtag", "buildtag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest := filepath.Join(t.TempDir(), "pinger_mock.go")
+	if err := m.MockToFile(dest, "Pinger"); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(b), "//go:build linux || darwin\n\n") {
+		t.Errorf("generated mock doesn't start with the source package's build constraint:\n%s", b)
+	}
+	assertCompiles(t, b, "testdata/buildtag")
+}
+
+// TestMockToFileRefusesToOverwriteHandWrittenFile checks that MockToFile
+// won't clobber a file at its destination that doesn't carry the
+// "AUTOGENERATED BY MOQ" marker, but will overwrite a file that does
+// (e.g. regenerating after a previous run).
+func TestMockToFileRefusesToOverwriteHandWrittenFile(t *testing.T) {
+	m, err := New("testdata/buildtag", "buildtag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest := filepath.Join(t.TempDir(), "pinger_mock.go")
+	if err := os.WriteFile(dest, []byte("package buildtag\n\n// hand-written\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.MockToFile(dest, "Pinger"); err == nil {
+		t.Fatal("MockToFile overwrote a hand-written file, want an error")
+	}
+	b, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "hand-written") {
+		t.Errorf("MockToFile modified a hand-written file it should have refused to touch:\n%s", b)
+	}
+
+	if err := os.WriteFile(dest, []byte("// AUTOGENERATED BY MOQ; DO NOT EDIT.\npackage buildtag\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.MockToFile(dest, "Pinger"); err != nil {
+		t.Fatalf("MockToFile refused to overwrite its own prior output: %v", err)
+	}
+	b, err = os.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "func (mock *PingerMock) Ping() error {") {
+		t.Errorf("MockToFile did not regenerate the mock:\n%s", b)
+	}
+}
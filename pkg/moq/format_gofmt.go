@@ -0,0 +1,13 @@
+//go:build !moq_goimports
+
+package moq
+
+import "go/format"
+
+// formatSource runs generated source through gofmt. Build with the
+// moq_goimports tag (see format_goimports.go) to run goimports instead,
+// which additionally reconciles the import block against what the file
+// actually references.
+func formatSource(src []byte) ([]byte, error) {
+	return format.Source(src)
+}
@@ -0,0 +1,14 @@
+//go:build moq_goimports
+
+package moq
+
+import "golang.org/x/tools/imports"
+
+// formatSource runs generated source through goimports, which runs
+// gofmt and additionally adds or removes imports to match what the file
+// actually uses. It requires golang.org/x/tools as a dependency, which
+// is why it's opt-in behind the moq_goimports build tag rather than the
+// default.
+func formatSource(src []byte) ([]byte, error) {
+	return imports.Process("", src, nil)
+}
@@ -0,0 +1,43 @@
+package moq
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGenerateDoesNotLeakImportsAcrossDestinations reproduces generating two
+// interfaces with different import needs into two different destination
+// files from the same Mocker, and checks that each file only gets its own
+// imports.
+func TestGenerateDoesNotLeakImportsAcrossDestinations(t *testing.T) {
+	m, err := New("testdata/fooer", "fooer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "outA"), 0o755)
+	os.MkdirAll(filepath.Join(dir, "outB"), 0o755)
+	destA := filepath.Join(dir, "outA", "fooer_mock.go")
+	destB := filepath.Join(dir, "outB", "barer_mock.go")
+
+	err = m.Generate(
+		MockSpec{Source: "testdata/fooer", Interface: "Fooer", Destination: destA},
+		MockSpec{Source: "testdata/barer", Interface: "Barer", Destination: destB},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(destB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "net/http") {
+		t.Errorf("destB leaked destA's net/http import:\n%s", b)
+	}
+	if !strings.Contains(string(b), `"time"`) {
+		t.Errorf("destB is missing its own time import:\n%s", b)
+	}
+}
@@ -0,0 +1,69 @@
+package moq
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestMockGenericMultipleTypeParamsConstraintUnionsVariadic mocks Store,
+// a generic interface with multiple type parameters, a union
+// constraint and a variadic generic parameter, and checks the
+// generated mock stays generic rather than being instantiated.
+func TestMockGenericMultipleTypeParamsConstraintUnionsVariadic(t *testing.T) {
+	m, err := New("testdata/generics", "generics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := m.Mock(&buf, "Store"); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	if !strings.Contains(out, "type StoreMock[K comparable, V any, N Number] struct {") {
+		t.Errorf("generated mock is missing the expected type parameter list:\n%s", out)
+	}
+	if !strings.Contains(out, "func (mock *StoreMock[K, V, N]) Sum(nums ...N) N {") {
+		t.Errorf("generated mock is missing the expected variadic Sum method:\n%s", out)
+	}
+}
+
+// TestMockGenericInstantiatedOnCommandLine mocks Store instantiated
+// with concrete type arguments, the way the moq CLI accepts
+// "Cache[int]"-style interface names, and checks the generated mock is
+// concrete rather than carrying a type parameter list.
+func TestMockGenericInstantiatedOnCommandLine(t *testing.T) {
+	m, err := New("testdata/generics", "generics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	outDir := filepath.Join(dir, "out")
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	dest := filepath.Join(outDir, "store_mock.go")
+	err = m.Generate(MockSpec{
+		Source:      "testdata/generics",
+		Interface:   "Store[string, int, float64]",
+		Destination: dest,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(b)
+
+	if strings.Contains(out, "StoreMock[") {
+		t.Errorf("instantiated mock should not carry a type parameter list:\n%s", out)
+	}
+	if !strings.Contains(out, "func (mock *StoreMock) Sum(nums ...float64) float64 {") {
+		t.Errorf("generated mock is missing the expected concrete Sum method:\n%s", out)
+	}
+}
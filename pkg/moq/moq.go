@@ -1,6 +1,7 @@
 package moq
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"go/ast"
@@ -9,6 +10,8 @@ import (
 	"go/types"
 	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 )
@@ -21,11 +24,48 @@ type Mocker struct {
 	pkgs    map[string]*ast.Package
 	pkgName string
 
+	expectations bool
+	callLog      bool
+
 	imports map[string]bool
+
+	// reflectIfaces holds interfaces discovered via NewFromReflect,
+	// already converted to obj so Mock doesn't need a types.Package to
+	// read them from.
+	reflectIfaces map[string]obj
+
+	// pkgCache holds the parsed and type-checked contents of every
+	// source directory Generate has visited so far, keyed by directory,
+	// so mocking several interfaces out of the same package costs one
+	// parse and one types.Config.Check rather than one per interface.
+	pkgCache map[string]*pkgInfo
+}
+
+// Option configures optional behaviour of a Mocker.
+type Option func(*Mocker)
+
+// WithExpectations controls whether Mock also emits a gomock-style
+// EXPECT() recorder alongside the existing XxxFunc fields. It defaults to
+// false, so existing consumers of XxxFunc are unaffected.
+func WithExpectations(enabled bool) Option {
+	return func(m *Mocker) {
+		m.expectations = enabled
+	}
+}
+
+// WithCallLog controls whether Mock also emits an ordered mock.Calls log
+// of every call made to any method, plus AssertCalled,
+// AssertCalledInOrder and AssertNumberOfCalls helpers to make
+// assertions against it. It defaults to false, alongside CallsTo's
+// existing per-method call details.
+func WithCallLog(enabled bool) Option {
+	return func(m *Mocker) {
+		m.callLog = enabled
+	}
 }
 
 // New makes a new Mocker for the specified package directory.
-func New(src, packageName string) (*Mocker, error) {
+func New(src, packageName string, opts ...Option) (*Mocker, error) {
 	fset := token.NewFileSet()
 	noTestFiles := func(i os.FileInfo) bool {
 		return !strings.HasSuffix(i.Name(), "_test.go")
@@ -50,14 +90,18 @@ func New(src, packageName string) (*Mocker, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Mocker{
+	mocker := &Mocker{
 		src:     src,
 		tmpl:    tmpl,
 		fset:    fset,
 		pkgs:    pkgs,
 		pkgName: packageName,
 		imports: make(map[string]bool),
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(mocker)
+	}
+	return mocker, nil
 }
 
 // Mock generates a mock for the specified interface name.
@@ -66,8 +110,26 @@ func (m *Mocker) Mock(w io.Writer, name ...string) error {
 		return errors.New("must specify one interface")
 	}
 	doc := doc{
-		PackageName: m.pkgName,
-		Imports:     moqImports,
+		PackageName:  m.pkgName,
+		Imports:      append([]string{}, moqImports...),
+		Expectations: m.expectations,
+		CallLog:      m.callLog,
+	}
+	if m.expectations || m.callLog {
+		doc.Imports = append(doc.Imports, moqRuntimeImportPath)
+	}
+	if m.reflectIfaces != nil {
+		for _, n := range name {
+			obj, ok := m.reflectIfaces[n]
+			if !ok {
+				return fmt.Errorf("cannot find interface %s", n)
+			}
+			doc.Objects = append(doc.Objects, obj)
+		}
+		for pkgToImport := range m.imports {
+			doc.Imports = append(doc.Imports, pkgToImport)
+		}
+		return m.render(w, doc)
 	}
 	for _, pkg := range m.pkgs {
 		i := 0
@@ -81,50 +143,407 @@ func (m *Mocker) Mock(w io.Writer, name ...string) error {
 		if err != nil {
 			return err
 		}
+		qual := m.qualifierFor(m.pkgName, m.imports)
 		for _, n := range name {
-			iface := tpkg.Scope().Lookup(n)
-			if iface == nil {
-				return fmt.Errorf("cannot find interface %s", n)
+			o, err := m.buildObj(m.fset, tpkg, n, "", qual)
+			if err != nil {
+				return err
 			}
-			if !types.IsInterface(iface.Type()) {
-				return fmt.Errorf("%s (%s) not an interface", n, iface.Type().String())
-			}
-			iiface := iface.Type().Underlying().(*types.Interface).Complete()
-			obj := obj{
-				InterfaceName: n,
-			}
-			for i := 0; i < iiface.NumMethods(); i++ {
-				meth := iiface.Method(i)
-				sig := meth.Type().(*types.Signature)
-				method := &method{
-					Name: meth.Name(),
+			doc.Objects = append(doc.Objects, o)
+		}
+	}
+	for pkgToImport := range m.imports {
+		doc.Imports = append(doc.Imports, pkgToImport)
+	}
+	return m.render(w, doc)
+}
+
+// buildObj resolves name - optionally an instantiated generic name such
+// as "Cache[int]" - against tpkg, flattens its method set and returns the
+// obj the template renders it from. qual decides how types from other
+// packages are qualified, and must treat whichever package the resulting
+// mock will be declared in as "self". mockName overrides the generated
+// mock's type name; pass "" to default to name+"Mock".
+func (m *Mocker) buildObj(fset *token.FileSet, tpkg *types.Package, name, mockName string, qual types.Qualifier) (obj, error) {
+	baseName, typeArgExprs := splitGenericName(name)
+	iface := tpkg.Scope().Lookup(baseName)
+	if iface == nil {
+		return obj{}, fmt.Errorf("cannot find interface %s", baseName)
+	}
+	ifaceType := iface.Type()
+	var typeParams []*typeParam
+	if named, ok := ifaceType.(*types.Named); ok {
+		if tparams := named.TypeParams(); tparams != nil && tparams.Len() > 0 {
+			if len(typeArgExprs) > 0 {
+				instantiated, err := m.instantiate(fset, tpkg, named, typeArgExprs)
+				if err != nil {
+					return obj{}, fmt.Errorf("%s: %w", name, err)
+				}
+				ifaceType = instantiated
+			} else {
+				for i := 0; i < tparams.Len(); i++ {
+					tp := tparams.At(i)
+					typeParams = append(typeParams, &typeParam{
+						Name:       tp.Obj().Name(),
+						Constraint: types.TypeString(tp.Constraint(), qual),
+					})
 				}
-				obj.Methods = append(obj.Methods, method)
-				method.Params = m.extractArgs(sig, sig.Params(), "in%d")
-				method.Returns = m.extractArgs(sig, sig.Results(), "out%d")
 			}
-			doc.Objects = append(doc.Objects, obj)
 		}
 	}
-	for pkgToImport := range m.imports {
+	if !types.IsInterface(ifaceType) {
+		return obj{}, fmt.Errorf("%s (%s) not an interface", baseName, ifaceType.String())
+	}
+	iiface := ifaceType.Underlying().(*types.Interface)
+	methods, err := m.flattenInterfaceMethods(iiface, baseName, qual)
+	if err != nil {
+		return obj{}, err
+	}
+	if mockName == "" {
+		mockName = baseName + "Mock"
+	}
+	o := obj{
+		InterfaceName: baseName,
+		MockName:      mockName,
+		TypeParams:    typeParams,
+	}
+	for _, meth := range methods {
+		sig := meth.Type().(*types.Signature)
+		method := &method{
+			Name: meth.Name(),
+		}
+		o.Methods = append(o.Methods, method)
+		method.Params = m.extractArgs(sig.Params(), "in%d", qual, sig.Variadic())
+		method.Returns = m.extractArgs(sig.Results(), "out%d", qual, false)
+	}
+	return o, nil
+}
+
+// MockSpec describes a single mock Generate should produce: Interface,
+// declared in the package at Source, rendered as MockName and written to
+// Destination. Specs that share a Destination are rendered together into
+// that one file, in the order they're given.
+type MockSpec struct {
+	// Source is the directory containing the package that declares
+	// Interface.
+	Source string
+	// Interface is the interface to mock, as it's declared in Source. It
+	// may carry an instantiation, e.g. "Cache[int]", for generic
+	// interfaces.
+	Interface string
+	// MockName overrides the name of the generated mock type, and the
+	// names derived from it (its EXPECT recorder, its call-log types).
+	// It defaults to Interface+"Mock", the same convention Mock uses -
+	// set it to mock multiple interfaces named Interface into the same
+	// Destination, or to match an existing mock_names-style naming
+	// scheme without renaming the source interface.
+	MockName string
+	// Destination is the file Generate writes the mock to.
+	Destination string
+}
+
+// Generate mocks every spec, grouping specs that share a Destination into
+// a single file written once. It supports mocking interfaces out of
+// several different Source packages in one call. Parsing and
+// type-checking a given Source is cached across specs, so mocking N
+// interfaces out of the same package costs one types.Config.Check, not
+// N the way looping over Mock does.
+func (m *Mocker) Generate(specs ...MockSpec) error {
+	if len(specs) == 0 {
+		return errors.New("must specify at least one mock")
+	}
+
+	var destOrder []string
+	byDest := make(map[string][]MockSpec)
+	for _, spec := range specs {
+		if spec.Source == "" || spec.Interface == "" || spec.Destination == "" {
+			return errors.New("moq: MockSpec requires Source, Interface and Destination")
+		}
+		if _, ok := byDest[spec.Destination]; !ok {
+			destOrder = append(destOrder, spec.Destination)
+		}
+		byDest[spec.Destination] = append(byDest[spec.Destination], spec)
+	}
+
+	for _, dest := range destOrder {
+		if err := m.generateFile(dest, byDest[dest]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateFile renders specs - all sharing Destination - into a single
+// file at that path.
+func (m *Mocker) generateFile(dest string, specs []MockSpec) error {
+	doc := doc{
+		PackageName:  m.destPackageName(dest),
+		Imports:      append([]string{}, moqImports...),
+		Expectations: m.expectations,
+		CallLog:      m.callLog,
+	}
+	if m.expectations || m.callLog {
+		doc.Imports = append(doc.Imports, moqRuntimeImportPath)
+	}
+
+	imports := make(map[string]bool)
+	qual := m.qualifierFor(doc.PackageName, imports)
+	for _, spec := range specs {
+		info, err := m.loadPkgInfo(spec.Source)
+		if err != nil {
+			return fmt.Errorf("%s: %w", spec.Source, err)
+		}
+		o, err := m.buildObj(info.fset, info.tpkg, spec.Interface, spec.MockName, qual)
+		if err != nil {
+			return fmt.Errorf("%s: %w", spec.Source, err)
+		}
+		doc.Objects = append(doc.Objects, o)
+	}
+	for pkgToImport := range imports {
 		doc.Imports = append(doc.Imports, pkgToImport)
 	}
-	err := m.tmpl.Execute(w, doc)
+
+	var buf bytes.Buffer
+	if err := m.render(&buf, doc); err != nil {
+		return err
+	}
+	return writeGenerated(dest, buf.Bytes())
+}
+
+// pkgInfo is the parsed and type-checked contents of a single source
+// package directory, cached in Mocker.pkgCache.
+type pkgInfo struct {
+	fset *token.FileSet
+	tpkg *types.Package
+}
+
+// loadPkgInfo parses and type-checks the package at src, caching the
+// result so a later call for the same src is free.
+func (m *Mocker) loadPkgInfo(src string) (*pkgInfo, error) {
+	if info, ok := m.pkgCache[src]; ok {
+		return info, nil
+	}
+
+	fset := token.NewFileSet()
+	noTestFiles := func(i os.FileInfo) bool {
+		return !strings.HasSuffix(i.Name(), "_test.go")
+	}
+	pkgs, err := parser.ParseDir(fset, src, noTestFiles, parser.SpuriousErrors)
+	if err != nil {
+		return nil, err
+	}
+	var files []*ast.File
+	found := false
+	for pkgName, pkg := range pkgs {
+		if strings.Contains(pkgName, "_test") {
+			continue
+		}
+		for _, file := range pkg.Files {
+			files = append(files, file)
+		}
+		found = true
+		break
+	}
+	if !found {
+		return nil, fmt.Errorf("failed to determine package name for %s", src)
+	}
+
+	conf := types.Config{Importer: newImporter(src)}
+	tpkg, err := conf.Check(src, fset, files, nil)
 	if err != nil {
+		return nil, err
+	}
+
+	info := &pkgInfo{fset: fset, tpkg: tpkg}
+	if m.pkgCache == nil {
+		m.pkgCache = make(map[string]*pkgInfo)
+	}
+	m.pkgCache[src] = info
+	return info, nil
+}
+
+// destPackageName determines the package clause Generate should give the
+// file at dest: whatever package already lives in dest's directory, or -
+// if that directory has no Go sources yet - a name derived from it.
+func (m *Mocker) destPackageName(dest string) string {
+	dir := filepath.Dir(dest)
+	fset := token.NewFileSet()
+	noTestFiles := func(i os.FileInfo) bool {
+		return !strings.HasSuffix(i.Name(), "_test.go")
+	}
+	if pkgs, err := parser.ParseDir(fset, dir, noTestFiles, parser.SpuriousErrors); err == nil {
+		for pkgName := range pkgs {
+			if !strings.Contains(pkgName, "_test") {
+				return pkgName
+			}
+		}
+	}
+	return filepath.Base(dir)
+}
+
+// render executes the template against doc and writes the result to w.
+// doc.Imports is sorted first so that import order - and therefore the
+// generated bytes - doesn't depend on map iteration order. The rendered
+// source is then passed through formatSource (gofmt, or goimports when
+// built with the moq_goimports tag) before being written out, so output
+// is always valid, canonically formatted Go.
+func (m *Mocker) render(w io.Writer, doc doc) error {
+	sort.Strings(doc.Imports)
+	var buf bytes.Buffer
+	if err := m.tmpl.Execute(&buf, doc); err != nil {
 		return err
 	}
-	return nil
+	formatted, err := formatSource(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting generated mock: %w", err)
+	}
+	_, err = w.Write(formatted)
+	return err
 }
 
-func (m *Mocker) packageQualifier(pkg *types.Package) string {
-	if m.pkgName == pkg.Name() {
-		return ""
+// splitGenericName splits a command-line interface name that may carry an
+// instantiation, such as "Cache[int, string]", into its base name and the
+// (unparsed) type argument expressions. Names without a trailing
+// "[...]" are returned unchanged with no type arguments.
+func splitGenericName(n string) (base string, typeArgs []string) {
+	open := strings.IndexByte(n, '[')
+	if open < 0 || !strings.HasSuffix(n, "]") {
+		return n, nil
 	}
-	m.imports[pkg.Path()] = true
-	return pkg.Name()
+	base = n[:open]
+	inner := n[open+1 : len(n)-1]
+	depth := 0
+	start := 0
+	for i, r := range inner {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				typeArgs = append(typeArgs, strings.TrimSpace(inner[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	typeArgs = append(typeArgs, strings.TrimSpace(inner[start:]))
+	return base, typeArgs
+}
+
+// instantiate resolves typeArgExprs against tpkg and instantiates named
+// with them, producing the fully concrete interface type that
+// "Name[int, string]" on the command line refers to.
+func (m *Mocker) instantiate(fset *token.FileSet, tpkg *types.Package, named *types.Named, typeArgExprs []string) (types.Type, error) {
+	targs := make([]types.Type, len(typeArgExprs))
+	for i, exprStr := range typeArgExprs {
+		t, err := m.evalTypeExpr(fset, tpkg, exprStr)
+		if err != nil {
+			return nil, err
+		}
+		targs[i] = t
+	}
+	return types.Instantiate(nil, named, targs, true)
+}
+
+// evalTypeExpr resolves a type expression, such as "int" or
+// "map[string]Foo", against the scope of tpkg.
+func (m *Mocker) evalTypeExpr(fset *token.FileSet, tpkg *types.Package, exprStr string) (types.Type, error) {
+	expr, err := parser.ParseExpr(exprStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid type argument %q: %w", exprStr, err)
+	}
+	info := &types.Info{Types: make(map[ast.Expr]types.TypeAndValue)}
+	if err := types.CheckExpr(fset, tpkg, token.NoPos, expr, info); err != nil {
+		return nil, fmt.Errorf("invalid type argument %q: %w", exprStr, err)
+	}
+	tv, ok := info.Types[expr]
+	if !ok {
+		return nil, fmt.Errorf("could not resolve type argument %q", exprStr)
+	}
+	return tv.Type, nil
 }
 
-func (m *Mocker) extractArgs(sig *types.Signature, list *types.Tuple, nameFormat string) []*param {
+// qualifierFor returns a types.Qualifier that renders a reference to a
+// package unqualified when that package is selfPkgName - the package the
+// resulting mock will be declared in - and otherwise records the
+// package's import path in imports so the caller can add it to the
+// generated file's import block. imports is scoped to a single generated
+// file: Mock passes m.imports, its own per-Mocker accumulator, while
+// Generate passes a map local to the destination file it's rendering, so
+// imports pulled in by one destination never leak into another sharing
+// the same Mocker.
+func (m *Mocker) qualifierFor(selfPkgName string, imports map[string]bool) types.Qualifier {
+	return func(pkg *types.Package) string {
+		if selfPkgName == pkg.Name() {
+			return ""
+		}
+		imports[pkg.Path()] = true
+		return pkg.Name()
+	}
+}
+
+// flattenInterfaceMethods walks iface's own methods and, transitively,
+// those of every interface it embeds, resolving the flattened method set
+// by hand instead of relying solely on types.Interface.Complete(). Doing
+// so explicitly lets it surface a clear error, rather than a generic
+// type-checker failure, if two embedded interfaces disagree on a
+// method's signature. Methods are returned with an interface's own
+// methods (in the order go/types lists them, sorted by name) before
+// those of the interfaces it embeds, each embedded interface in turn,
+// and the outermost interface's own declaration of a name winning over
+// any embedded interface that repeats it with an identical signature.
+// Packages are registered with qual lazily, only as extractArgs renders
+// a parameter or return type that actually needs qualifying - an
+// embedded interface's own package is never registered just because it
+// was embedded, since the generated mock never mentions it otherwise
+// and an unused import would fail to build.
+func (m *Mocker) flattenInterfaceMethods(iface *types.Interface, ifaceName string, qual types.Qualifier) ([]*types.Func, error) {
+	seen := make(map[string]*types.Func)
+	var order []string
+
+	var walk func(i *types.Interface) error
+	walk = func(i *types.Interface) error {
+		for mi := 0; mi < i.NumExplicitMethods(); mi++ {
+			meth := i.ExplicitMethod(mi)
+			if existing, ok := seen[meth.Name()]; ok {
+				if !types.Identical(existing.Type(), meth.Type()) {
+					return fmt.Errorf("%s: embedded interfaces disagree on the signature of method %s", ifaceName, meth.Name())
+				}
+				continue
+			}
+			seen[meth.Name()] = meth
+			order = append(order, meth.Name())
+		}
+		for e := 0; e < i.NumEmbeddeds(); e++ {
+			embedded := i.EmbeddedType(e)
+			embeddedIface, ok := embedded.Underlying().(*types.Interface)
+			if !ok {
+				continue
+			}
+			if err := walk(embeddedIface); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(iface); err != nil {
+		return nil, err
+	}
+
+	methods := make([]*types.Func, len(order))
+	for i, name := range order {
+		methods[i] = seen[name]
+	}
+	return methods, nil
+}
+
+// extractArgs renders list (a signature's parameters or results) as
+// params. variadic should be sig.Variadic() when list is the
+// signature's parameters, and false for its results - a signature's
+// "variadic-ness" describes its last parameter, never its last result,
+// even though both are *types.Tuple.
+func (m *Mocker) extractArgs(list *types.Tuple, nameFormat string, qual types.Qualifier, variadic bool) []*param {
 	var params []*param
 	listLen := list.Len()
 	for ii := 0; ii < listLen; ii++ {
@@ -133,13 +552,13 @@ func (m *Mocker) extractArgs(sig *types.Signature, list *types.Tuple, nameFormat
 		if name == "" {
 			name = fmt.Sprintf(nameFormat, ii+1)
 		}
-		typename := types.TypeString(p.Type(), m.packageQualifier)
+		typename := types.TypeString(p.Type(), qual)
 		// check for final variadic argument
-		variadic := sig.Variadic() && ii == listLen-1 && typename[0:2] == "[]"
+		isVariadic := variadic && ii == listLen-1 && strings.HasPrefix(typename, "[]")
 		param := &param{
 			Name:     name,
 			Type:     typename,
-			Variadic: variadic,
+			Variadic: isVariadic,
 		}
 		params = append(params, param)
 	}
@@ -147,15 +566,61 @@ func (m *Mocker) extractArgs(sig *types.Signature, list *types.Tuple, nameFormat
 }
 
 type doc struct {
-	PackageName string
-	Objects     []obj
-	Imports     []string
+	PackageName  string
+	Objects      []obj
+	Imports      []string
+	Expectations bool
+	CallLog      bool
 }
 
 type obj struct {
+	// InterfaceName is the name of the interface being mocked, as it
+	// appears in its source package.
 	InterfaceName string
-	Methods       []*method
+	// MockName is the name of the generated mock type. It defaults to
+	// InterfaceName+"Mock", but Generate lets callers override it via
+	// MockSpec.MockName.
+	MockName   string
+	Methods    []*method
+	TypeParams []*typeParam
+}
+
+// typeParam is one entry of a generic interface's type parameter list,
+// e.g. the "T any" in "Cache[T any]".
+type typeParam struct {
+	Name       string
+	Constraint string
+}
+
+// TypeParamDecl renders the mock's type parameter list as it appears in
+// a generic type declaration, e.g. "[T any, U comparable]". It is empty
+// for non-generic interfaces, and for interfaces instantiated on the
+// command line (e.g. "Cache[int]"), since those produce a concrete mock.
+func (o obj) TypeParamDecl() string {
+	if len(o.TypeParams) == 0 {
+		return ""
+	}
+	parts := make([]string, len(o.TypeParams))
+	for i, tp := range o.TypeParams {
+		parts[i] = fmt.Sprintf("%s %s", tp.Name, tp.Constraint)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
 }
+
+// TypeArgs renders the mock's type parameters as bare arguments, e.g.
+// "[T, U]", for use wherever the generic mock type is referenced, such
+// as on the receiver of its methods.
+func (o obj) TypeArgs() string {
+	if len(o.TypeParams) == 0 {
+		return ""
+	}
+	names := make([]string, len(o.TypeParams))
+	for i, tp := range o.TypeParams {
+		names[i] = tp.Name
+	}
+	return "[" + strings.Join(names, ", ") + "]"
+}
+
 type method struct {
 	Name    string
 	Params  []*param
@@ -189,6 +654,32 @@ func (m *method) ReturnArglist() string {
 	return strings.Join(params, ", ")
 }
 
+// ArgTypeList renders the method's parameter types only, comma
+// separated, for use in a func(...) type expression.
+func (m *method) ArgTypeList() string {
+	types := make([]string, len(m.Params))
+	for i, p := range m.Params {
+		types[i] = p.TypeString()
+	}
+	return strings.Join(types, ", ")
+}
+
+// DoFuncType renders the func(...) type that a Do/DoAndReturn action must
+// satisfy in order to be invoked in place of this method.
+func (m *method) DoFuncType() string {
+	return fmt.Sprintf("func(%s) %s", m.ArgTypeList(), m.ReturnArglist())
+}
+
+// ReturnNames renders the ret0, ret1, ... identifiers used to collect an
+// expectation's recorded Return values.
+func (m *method) ReturnNames() string {
+	names := make([]string, len(m.Returns))
+	for i := range m.Returns {
+		names[i] = fmt.Sprintf("ret%d", i)
+	}
+	return strings.Join(names, ", ")
+}
+
 type param struct {
 	Name     string
 	Type     string
@@ -225,6 +716,12 @@ var templateFuncs = template.FuncMap{
 // moqImports are the imports all moq files get.
 var moqImports = []string{"sync"}
 
+// moqRuntimeImportPath is the support package imported by mocks generated
+// with WithExpectations(true) or WithCallLog(true). It is imported under
+// the "moqruntime" name
+// so it never collides with the mocked package itself.
+const moqRuntimeImportPath = "github.com/mkfsn/moq/pkg/moq/runtime"
+
 // moqTemplate is the template for mocked code.
 var moqTemplate = `package {{.PackageName}}
 
@@ -233,16 +730,20 @@ var moqTemplate = `package {{.PackageName}}
 
 import (
 {{- range .Imports }}
+{{- if eq . "github.com/mkfsn/moq/pkg/moq/runtime" }}
+	moqruntime "{{.}}"
+{{- else }}
 	"{{.}}"
 {{- end }}
+{{- end }}
 )
 {{ range $i, $obj := .Objects }}
-// {{.InterfaceName}}Mock is a mock implementation of {{.InterfaceName}}.
+// {{.MockName}} is a mock implementation of {{.InterfaceName}}.
 //
 //     func TestSomethingThatUses{{.InterfaceName}}(t *testing.T) {
 //
 //         // make and configure a mocked {{.InterfaceName}}
-//         mocked{{.InterfaceName}} := &{{.InterfaceName}}Mock{ {{ range .Methods }}
+//         mocked{{.InterfaceName}} := &{{.MockName}}{ {{ range .Methods }}
 //             {{.Name}}Func: func({{ .Arglist }}) {{.ReturnArglist}} {
 // 	               panic("TODO: mock out the {{.Name}} method")
 //             },{{- end }}
@@ -258,7 +759,7 @@ import (
 //         //     }
 //         
 //     }
-type {{.InterfaceName}}Mock struct {
+type {{.MockName}}{{.TypeParamDecl}} struct {
 {{- range .Methods }}
 	// {{.Name}}Func mocks the {{.Name}} method.
 	{{.Name}}Func func({{ .Arglist }}) {{.ReturnArglist}}
@@ -276,12 +777,160 @@ type {{.InterfaceName}}Mock struct {
 		}
 {{- end }}
 	}
+{{- if $.CallLog }}
+
+	// Calls is the ordered log of every call made to any method of this
+	// mock, each wrapped in its own {{.MockName}}*Call type. Use
+	// AssertCalled, AssertCalledInOrder or AssertNumberOfCalls to make
+	// assertions against it.
+	Calls []{{.MockName}}Call{{.TypeArgs}}
+	callsMu sync.Mutex
+{{- end }}
+{{- if $.Expectations }}
+
+	// ctrl matches calls against expectations recorded via EXPECT, when
+	// the mock was built with New{{.MockName}} rather than &{{.MockName}}{}.
+	ctrl *moqruntime.Controller
+{{- end }}
+}
+{{ if $.CallLog }}
+// {{.MockName}}Call is the sum type of every entry
+// {{.MockName}}'s Calls log can hold, one concrete type per
+// method.
+type {{.MockName}}Call{{.TypeParamDecl}} interface {
+	is{{.MockName}}Call()
+	Method() string
+	Args() []interface{}
+}
+{{ range .Methods }}
+// {{$obj.MockName}}{{.Name}}Call is the logged record of a call to {{.Name}}.
+type {{$obj.MockName}}{{.Name}}Call{{$obj.TypeParamDecl}} struct {
+	{{- range .Params }}
+	{{ .Name | Exported }} {{ .Type }}
+	{{- end }}
+}
+
+func (c {{$obj.MockName}}{{.Name}}Call{{$obj.TypeArgs}}) is{{$obj.MockName}}Call() {}
+
+// Method returns "{{.Name}}".
+func (c {{$obj.MockName}}{{.Name}}Call{{$obj.TypeArgs}}) Method() string { return "{{.Name}}" }
+
+// Args returns the arguments this call was made with, in order.
+func (c {{$obj.MockName}}{{.Name}}Call{{$obj.TypeArgs}}) Args() []interface{} {
+	return []interface{}{ {{- range .Params }}c.{{ .Name | Exported }}, {{- end }} }
+}
+{{ end }}
+// AssertCalled reports a failure via t unless method was called with
+// arguments accepted by matchers at least once.
+func (mock *{{.MockName}}{{.TypeArgs}}) AssertCalled(t moqruntime.TestReporter, method string, matchers ...moqruntime.Matcher) {
+	moqruntime.AssertCalled(t, mock.loggedCalls(), method, matchers...)
+}
+
+// AssertNumberOfCalls reports a failure via t unless method was called
+// exactly n times.
+func (mock *{{.MockName}}{{.TypeArgs}}) AssertNumberOfCalls(t moqruntime.TestReporter, method string, n int) {
+	moqruntime.AssertNumberOfCalls(t, mock.loggedCalls(), method, n)
+}
+
+// AssertCalledInOrder reports a failure via t unless every call in
+// calls appears in mock.Calls in the same relative order.
+func (mock *{{.MockName}}{{.TypeArgs}}) AssertCalledInOrder(t moqruntime.TestReporter, calls ...{{.MockName}}Call{{.TypeArgs}}) {
+	want := make([]moqruntime.LoggedCall, len(calls))
+	for i, c := range calls {
+		want[i] = c
+	}
+	moqruntime.AssertCalledInOrder(t, mock.loggedCalls(), want...)
+}
+
+func (mock *{{.MockName}}{{.TypeArgs}}) loggedCalls() []moqruntime.LoggedCall {
+	mock.callsMu.Lock()
+	defer mock.callsMu.Unlock()
+	calls := make([]moqruntime.LoggedCall, len(mock.Calls))
+	for i, c := range mock.Calls {
+		calls[i] = c
+	}
+	return calls
+}
+{{- end }}
+{{ if $.Expectations }}
+// New{{.MockName}} makes a new {{.MockName}} whose calls
+// are matched against expectations recorded through EXPECT. Unsatisfied
+// or unexpected calls are reported to t.
+func New{{.MockName}}{{.TypeParamDecl}}(t moqruntime.TestReporter) *{{.MockName}}{{.TypeArgs}} {
+	return &{{.MockName}}{{.TypeArgs}}{ctrl: moqruntime.NewController(t)}
+}
+
+// {{.MockName}}Recorder records expectations for a {{.MockName}}.
+type {{.MockName}}Recorder{{.TypeParamDecl}} struct {
+	ctrl *moqruntime.Controller
+}
+
+// EXPECT returns an object that allows the caller to record expected
+// calls to mock's methods.
+func (mock *{{.MockName}}{{.TypeArgs}}) EXPECT() *{{.MockName}}Recorder{{.TypeArgs}} {
+	return &{{.MockName}}Recorder{{.TypeArgs}}{ctrl: mock.ctrl}
+}
+{{ range .Methods }}
+// {{.Name}} records an expectation that {{.Name}} will be called with
+// arguments accepted by the given matchers.
+func (r *{{$obj.MockName}}Recorder{{$obj.TypeArgs}}) {{.Name}}({{ range $i, $p := .Params }}{{ if $i }}, {{ end }}{{ $p.Name }} moqruntime.Matcher{{ end }}) *moqruntime.Call {
+	return r.ctrl.RecordCall("{{.Name}}"{{ range .Params }}, {{.Name}}{{ end }})
 }
+{{ end }}
+{{- end }}
 {{ range .Methods }}
-// {{.Name}} calls {{.Name}}Func.
-func (mock *{{$obj.InterfaceName}}Mock) {{.Name}}({{.Arglist}}) {{.ReturnArglist}} {
+// {{.Name}} calls {{.Name}}Func{{ if $.Expectations }}, or matches the call against an expectation recorded via EXPECT when ctrl is set{{ end }}.
+func (mock *{{$obj.MockName}}{{$obj.TypeArgs}}) {{.Name}}({{.Arglist}}) {{.ReturnArglist}} {
+{{- if $.CallLog }}
+	mock.callsMu.Lock()
+	mock.Calls = append(mock.Calls, {{$obj.MockName}}{{.Name}}Call{{$obj.TypeArgs}}{
+		{{- range .Params }}
+		{{ .Name | Exported }}: {{ .Name }},
+		{{- end }}
+	})
+	mock.callsMu.Unlock()
+{{- end }}
+{{- if $.Expectations }}
+	if mock.ctrl != nil {
+		call := mock.ctrl.Call("{{.Name}}"{{ range .Params }}, {{.Name}}{{ end }})
+		if call == nil {
+			// mock.ctrl.Call reports the unexpected call via
+			// TestReporter.Fatalf, which *testing.T never returns from; a
+			// custom TestReporter is not guaranteed to behave the same, so
+			// this guards against continuing with a nil call.
+{{- if .Returns }}
+			return {{ range $i, $r := .Returns }}{{ if $i }}, {{ end }}*new({{$r.Type}}){{ end }}
+{{- else }}
+			return
+{{- end }}
+		}
+		if fn, ok := call.DoFn().({{.DoFuncType}}); ok {
+{{- if .Returns }}
+			return fn({{.ArgCallList}})
+{{- else }}
+			fn({{.ArgCallList}})
+			return
+{{- end }}
+		}
+{{- if .Returns }}
+		if fn, ok := call.DoFn().(func({{.ArgTypeList}})); ok {
+			fn({{.ArgCallList}})
+		}
+		rets := call.Returns()
+		{{- range $i, $r := .Returns }}
+		var ret{{$i}} {{$r.Type}}
+		if len(rets) > {{$i}} {
+			ret{{$i}}, _ = rets[{{$i}}].({{$r.Type}})
+		}
+		{{- end }}
+		return {{.ReturnNames}}
+{{- else }}
+		return
+{{- end }}
+	}
+{{- end }}
 	if mock.{{.Name}}Func == nil {
-		panic("moq: {{$obj.InterfaceName}}Mock.{{.Name}}Func is nil but was just called")
+		panic("moq: {{$obj.MockName}}.{{.Name}}Func is nil but was just called")
 	}
 	mock.CallsTo.lock{{.Name}}.Lock()
 	mock.CallsTo.{{.Name}} = append(mock.CallsTo.{{.Name}}, struct{
@@ -0,0 +1,352 @@
+package moq
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// NewFromReflect makes a new Mocker for one or more interfaces that live
+// in a third-party package, without requiring that package's source to
+// be vendored or copied locally first. It works the way mockgen's
+// reflect mode does: it writes a throwaway Go program that imports
+// importPath, uses package reflect on a nil-typed value of each named
+// interface to recover its method set, and runs that program with `go
+// run`. The decoded model is fed into the same obj/method/param types
+// that Mock already renders, so the rest of the pipeline is unaware the
+// interface wasn't parsed from source.
+//
+// destPkg is the package name the generated mock will belong to; types
+// that reflection reports as belonging to destPkg are rendered
+// unqualified, the same way qualifierFor treats the package the mock is
+// declared in.
+func NewFromReflect(importPath, destPkg string, interfaceNames ...string) (*Mocker, error) {
+	if len(interfaceNames) == 0 {
+		return nil, errors.New("must specify one interface")
+	}
+	model, err := runReflectProgram(importPath, interfaceNames)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New("moq").Funcs(templateFuncs).Parse(moqTemplate)
+	if err != nil {
+		return nil, err
+	}
+	m := &Mocker{
+		tmpl:          tmpl,
+		pkgName:       destPkg,
+		imports:       make(map[string]bool),
+		reflectIfaces: make(map[string]obj),
+	}
+	for _, iface := range model.Interfaces {
+		o := obj{InterfaceName: iface.Name, MockName: iface.Name + "Mock"}
+		for _, rm := range iface.Methods {
+			meth := &method{Name: rm.Name}
+			for ii, p := range rm.Params {
+				name := p.Name
+				if name == "" {
+					name = fmt.Sprintf("in%d", ii+1)
+				}
+				typename := m.renderReflectType(p.Type)
+				variadic := rm.Variadic && ii == len(rm.Params)-1 && len(typename) >= 2 && typename[0:2] == "[]"
+				meth.Params = append(meth.Params, &param{Name: name, Type: typename, Variadic: variadic})
+			}
+			for oi, r := range rm.Results {
+				name := r.Name
+				if name == "" {
+					name = fmt.Sprintf("out%d", oi+1)
+				}
+				meth.Returns = append(meth.Returns, &param{Name: name, Type: m.renderReflectType(r.Type)})
+			}
+			o.Methods = append(o.Methods, meth)
+		}
+		m.reflectIfaces[iface.Name] = o
+	}
+	return m, nil
+}
+
+// reflectModel is the JSON shape emitted by the program generated for
+// NewFromReflect. It mirrors just enough of go/types' structure that
+// renderReflectType can re-render each method's signature using this
+// Mocker's own package qualifier, rather than whatever import path the
+// subprocess happened to reflect from.
+type reflectModel struct {
+	Interfaces []reflectInterface `json:"interfaces"`
+}
+
+type reflectInterface struct {
+	Name    string          `json:"name"`
+	Methods []reflectMethod `json:"methods"`
+}
+
+type reflectMethod struct {
+	Name     string         `json:"name"`
+	Params   []reflectParam `json:"params"`
+	Results  []reflectParam `json:"results"`
+	Variadic bool           `json:"variadic"`
+}
+
+type reflectParam struct {
+	Name string      `json:"name"`
+	Type reflectType `json:"type"`
+}
+
+// reflectType structurally describes a Go type recovered via
+// reflect.Type: enough composite information (pointer/slice/map/chan/func
+// nesting) to re-render it, but with named types kept as a
+// (pkgPath, pkgName, name) triple instead of a flattened string so the
+// host process decides qualification itself.
+type reflectType struct {
+	Kind    string        `json:"kind"` // basic, pointer, slice, array, map, chan, func, interface, named
+	PkgPath string        `json:"pkgPath,omitempty"`
+	PkgName string        `json:"pkgName,omitempty"`
+	Name    string        `json:"name,omitempty"`
+	Len     int           `json:"len,omitempty"`
+	ChanDir string        `json:"chanDir,omitempty"`
+	Elem    *reflectType  `json:"elem,omitempty"`
+	Key     *reflectType  `json:"key,omitempty"`
+	Params  []reflectType `json:"params,omitempty"`
+	Results []reflectType `json:"results,omitempty"`
+}
+
+// renderReflectType renders t as Go source, adding any other package it
+// references to m.imports and stripping the qualifier for types that
+// belong to m.pkgName, the same convention qualifierFor uses for
+// source-parsed interfaces.
+func (m *Mocker) renderReflectType(t reflectType) string {
+	switch t.Kind {
+	case "pointer":
+		return "*" + m.renderReflectType(*t.Elem)
+	case "slice":
+		return "[]" + m.renderReflectType(*t.Elem)
+	case "array":
+		return fmt.Sprintf("[%d]%s", t.Len, m.renderReflectType(*t.Elem))
+	case "map":
+		return fmt.Sprintf("map[%s]%s", m.renderReflectType(*t.Key), m.renderReflectType(*t.Elem))
+	case "chan":
+		switch t.ChanDir {
+		case "recv":
+			return "<-chan " + m.renderReflectType(*t.Elem)
+		case "send":
+			return "chan<- " + m.renderReflectType(*t.Elem)
+		default:
+			return "chan " + m.renderReflectType(*t.Elem)
+		}
+	case "func":
+		params := make([]string, len(t.Params))
+		for i, p := range t.Params {
+			params[i] = m.renderReflectType(p)
+		}
+		results := make([]string, len(t.Results))
+		for i, r := range t.Results {
+			results[i] = m.renderReflectType(r)
+		}
+		switch len(results) {
+		case 0:
+			return fmt.Sprintf("func(%s)", strings.Join(params, ", "))
+		case 1:
+			return fmt.Sprintf("func(%s) %s", strings.Join(params, ", "), results[0])
+		default:
+			return fmt.Sprintf("func(%s) (%s)", strings.Join(params, ", "), strings.Join(results, ", "))
+		}
+	case "interface":
+		if t.Name == "" {
+			return "interface{}"
+		}
+		fallthrough
+	default: // basic, named
+		if t.PkgPath == "" {
+			return t.Name
+		}
+		if t.PkgName == m.pkgName {
+			return t.Name
+		}
+		m.imports[t.PkgPath] = true
+		return t.PkgName + "." + t.Name
+	}
+}
+
+// runReflectProgram writes a small Go program to a scratch directory and
+// runs it with `go run` from the caller's current directory, so module
+// resolution for importPath uses whatever go.mod already required it
+// there (the same way a hand-written program importing importPath
+// would). It decodes the program's JSON output on stdout.
+func runReflectProgram(importPath string, interfaceNames []string) (*reflectModel, error) {
+	src, err := renderReflectProgramSource(importPath, interfaceNames)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "moq-reflect-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	progFile := filepath.Join(dir, "moq_reflect_prog.go")
+	if err := os.WriteFile(progFile, src, 0o600); err != nil {
+		return nil, err
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("go", "run", progFile)
+	cmd.Dir = wd
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("reflecting on %s: %w: %s", importPath, err, stderr.String())
+	}
+	var model reflectModel
+	if err := json.Unmarshal(stdout.Bytes(), &model); err != nil {
+		return nil, fmt.Errorf("decoding reflect output for %s: %w", importPath, err)
+	}
+	return &model, nil
+}
+
+func renderReflectProgramSource(importPath string, interfaceNames []string) ([]byte, error) {
+	tmpl, err := template.New("reflectProg").Parse(reflectProgramTemplate)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, struct {
+		ImportPath string
+		Interfaces []string
+	}{ImportPath: importPath, Interfaces: interfaceNames})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// reflectProgramTemplate is compiled and run by NewFromReflect to dump
+// the method set of each requested interface as JSON on stdout. It
+// duplicates the reflectModel/reflectType shapes above, rather than
+// importing this package, since it's built and run standalone.
+const reflectProgramTemplate = `// Code generated by moq's reflect mode. DO NOT EDIT.
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"reflect"
+
+	target {{printf "%q" .ImportPath}}
+)
+
+type reflectModel struct {
+	Interfaces []reflectInterface ` + "`json:\"interfaces\"`" + `
+}
+
+type reflectInterface struct {
+	Name    string          ` + "`json:\"name\"`" + `
+	Methods []reflectMethod ` + "`json:\"methods\"`" + `
+}
+
+type reflectMethod struct {
+	Name     string         ` + "`json:\"name\"`" + `
+	Params   []reflectParam ` + "`json:\"params\"`" + `
+	Results  []reflectParam ` + "`json:\"results\"`" + `
+	Variadic bool           ` + "`json:\"variadic\"`" + `
+}
+
+type reflectParam struct {
+	Name string      ` + "`json:\"name\"`" + `
+	Type reflectType ` + "`json:\"type\"`" + `
+}
+
+type reflectType struct {
+	Kind    string        ` + "`json:\"kind\"`" + `
+	PkgPath string        ` + "`json:\"pkgPath,omitempty\"`" + `
+	PkgName string        ` + "`json:\"pkgName,omitempty\"`" + `
+	Name    string        ` + "`json:\"name,omitempty\"`" + `
+	Len     int           ` + "`json:\"len,omitempty\"`" + `
+	ChanDir string        ` + "`json:\"chanDir,omitempty\"`" + `
+	Elem    *reflectType  ` + "`json:\"elem,omitempty\"`" + `
+	Key     *reflectType  ` + "`json:\"key,omitempty\"`" + `
+	Params  []reflectType ` + "`json:\"params,omitempty\"`" + `
+	Results []reflectType ` + "`json:\"results,omitempty\"`" + `
+}
+
+func typeOf(t reflect.Type) reflectType {
+	switch t.Kind() {
+	case reflect.Ptr:
+		e := typeOf(t.Elem())
+		return reflectType{Kind: "pointer", Elem: &e}
+	case reflect.Slice:
+		e := typeOf(t.Elem())
+		return reflectType{Kind: "slice", Elem: &e}
+	case reflect.Array:
+		e := typeOf(t.Elem())
+		return reflectType{Kind: "array", Len: t.Len(), Elem: &e}
+	case reflect.Map:
+		k := typeOf(t.Key())
+		e := typeOf(t.Elem())
+		return reflectType{Kind: "map", Key: &k, Elem: &e}
+	case reflect.Chan:
+		e := typeOf(t.Elem())
+		dir := "both"
+		switch t.ChanDir() {
+		case reflect.RecvDir:
+			dir = "recv"
+		case reflect.SendDir:
+			dir = "send"
+		}
+		return reflectType{Kind: "chan", ChanDir: dir, Elem: &e}
+	case reflect.Func:
+		rt := reflectType{Kind: "func"}
+		for i := 0; i < t.NumIn(); i++ {
+			rt.Params = append(rt.Params, typeOf(t.In(i)))
+		}
+		for i := 0; i < t.NumOut(); i++ {
+			rt.Results = append(rt.Results, typeOf(t.Out(i)))
+		}
+		return rt
+	case reflect.Interface:
+		if t.NumMethod() == 0 && t.PkgPath() == "" {
+			return reflectType{Kind: "interface"}
+		}
+	}
+	if t.PkgPath() == "" {
+		return reflectType{Kind: "basic", Name: t.String()}
+	}
+	return reflectType{Kind: "named", PkgPath: t.PkgPath(), PkgName: path.Base(t.PkgPath()), Name: t.Name()}
+}
+
+func main() {
+	model := reflectModel{}
+	{{ range .Interfaces }}
+	{
+		var v *target.{{.}}
+		t := reflect.TypeOf(v).Elem()
+		iface := reflectInterface{Name: {{printf "%q" .}}}
+		for i := 0; i < t.NumMethod(); i++ {
+			meth := t.Method(i)
+			sig := meth.Type
+			rm := reflectMethod{Name: meth.Name, Variadic: sig.IsVariadic()}
+			for pi := 0; pi < sig.NumIn(); pi++ {
+				rm.Params = append(rm.Params, reflectParam{Type: typeOf(sig.In(pi))})
+			}
+			for ri := 0; ri < sig.NumOut(); ri++ {
+				rm.Results = append(rm.Results, reflectParam{Type: typeOf(sig.Out(ri))})
+			}
+			iface.Methods = append(iface.Methods, rm)
+		}
+		model.Interfaces = append(model.Interfaces, iface)
+	}
+	{{ end }}
+	enc := json.NewEncoder(os.Stdout)
+	if err := enc.Encode(model); err != nil {
+		panic(err)
+	}
+}
+`
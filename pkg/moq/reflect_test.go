@@ -0,0 +1,56 @@
+package moq
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestNewFromReflectEmbeddedInterface reflects on Thing, which embeds
+// io.Reader, from a throwaway module (NewFromReflect shells out to `go
+// run`, which needs a real module to resolve importPath against) and
+// checks the generated mock, which flattens Read promoted from
+// io.Reader alongside Thing's own Label, actually compiles.
+func TestNewFromReflectEmbeddedInterface(t *testing.T) {
+	modRoot := t.TempDir()
+	writeFile(t, filepath.Join(modRoot, "go.mod"), "module example.com/reflecttarget\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(modRoot, "thing.go"), `package thingpkg
+
+import "io"
+
+// Thing embeds io.Reader, exercising NewFromReflect's handling of a
+// promoted method from an embedded interface.
+type Thing interface {
+	io.Reader
+	Label() string
+}
+`)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(modRoot); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	m, err := NewFromReflect("example.com/reflecttarget", "thingpkg", "Thing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := m.Mock(&buf, "Thing"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Chdir(wd); err != nil {
+		t.Fatal(err)
+	}
+	assertCompiles(t, buf.Bytes(), modRoot)
+}
@@ -0,0 +1,79 @@
+package runtime
+
+// LoggedCall is implemented by every entry a generated mock's ordered
+// Calls log holds (one concrete type per mocked method, e.g.
+// FooMockBarCall), so AssertCalled, AssertCalledInOrder and
+// AssertNumberOfCalls can work generically across mocks and methods.
+type LoggedCall interface {
+	// Method returns the name of the method this entry records.
+	Method() string
+	// Args returns the method's arguments, in declaration order.
+	Args() []interface{}
+}
+
+func matchesArgs(args []interface{}, matchers []Matcher) bool {
+	if len(matchers) == 0 {
+		return true
+	}
+	if len(args) != len(matchers) {
+		return false
+	}
+	for i, m := range matchers {
+		if !m.Matches(args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func argsToMatchers(args []interface{}) []Matcher {
+	matchers := make([]Matcher, len(args))
+	for i, a := range args {
+		matchers[i] = Eq(a)
+	}
+	return matchers
+}
+
+// AssertCalled reports a failure via t unless calls contains at least
+// one entry for method whose arguments are all accepted by matchers.
+// Passing no matchers only checks that method was called at all.
+func AssertCalled(t TestReporter, calls []LoggedCall, method string, matchers ...Matcher) {
+	for _, c := range calls {
+		if c.Method() == method && matchesArgs(c.Args(), matchers) {
+			return
+		}
+	}
+	t.Errorf("moq: expected a call to %s matching the given arguments, but none was found", method)
+}
+
+// AssertNumberOfCalls reports a failure via t unless method appears
+// exactly n times in calls.
+func AssertNumberOfCalls(t TestReporter, calls []LoggedCall, method string, n int) {
+	got := 0
+	for _, c := range calls {
+		if c.Method() == method {
+			got++
+		}
+	}
+	if got != n {
+		t.Errorf("moq: expected %d call(s) to %s, got %d", n, method, got)
+	}
+}
+
+// AssertCalledInOrder reports a failure via t unless every entry in want
+// appears in calls, in the same relative order. Other calls are allowed
+// to appear in between; want's own arguments are matched by equality.
+func AssertCalledInOrder(t TestReporter, calls []LoggedCall, want ...LoggedCall) {
+	i := 0
+	for _, c := range calls {
+		if i == len(want) {
+			break
+		}
+		if c.Method() == want[i].Method() && matchesArgs(c.Args(), argsToMatchers(want[i].Args())) {
+			i++
+		}
+	}
+	if i != len(want) {
+		t.Errorf("moq: expected %d calls in order, found %d before running out of logged calls", len(want), i)
+	}
+}
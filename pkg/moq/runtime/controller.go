@@ -0,0 +1,239 @@
+package runtime
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Call is a single expectation recorded through a generated EXPECT()
+// recorder. It is returned so callers can chain Return, Do, DoAndReturn
+// and Times in the usual gomock-ish style.
+type Call struct {
+	method   string
+	matchers []Matcher
+
+	mu       sync.Mutex
+	doFn     interface{}
+	returns  []interface{}
+	minCalls int
+	maxCalls int
+	numCalls int
+}
+
+// NewCall creates a Call expecting method to be invoked with arguments
+// accepted by matchers. It defaults to expecting exactly one call; use
+// Times, MinTimes or MaxTimes to change that.
+func NewCall(method string, matchers ...Matcher) *Call {
+	return &Call{method: method, matchers: matchers, minCalls: 1, maxCalls: 1}
+}
+
+// Return sets the values the mocked method should return when this
+// expectation is matched.
+func (c *Call) Return(rets ...interface{}) *Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.returns = rets
+	return c
+}
+
+// Do registers fn to be called, for its side effects, when this
+// expectation is matched. fn must have the same parameter types as the
+// mocked method.
+func (c *Call) Do(fn interface{}) *Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.doFn = fn
+	return c
+}
+
+// DoAndReturn registers fn to be called when this expectation is matched
+// and to supply the returned values. fn must have the same signature as
+// the mocked method.
+func (c *Call) DoAndReturn(fn interface{}) *Call {
+	return c.Do(fn)
+}
+
+// Times sets the exact number of calls this expectation must see.
+func (c *Call) Times(n int) *Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.minCalls, c.maxCalls = n, n
+	return c
+}
+
+// MinTimes sets the minimum number of calls this expectation must see,
+// leaving the maximum unbounded.
+func (c *Call) MinTimes(n int) *Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.minCalls = n
+	c.maxCalls = -1
+	return c
+}
+
+// MaxTimes sets the maximum number of calls this expectation may see.
+func (c *Call) MaxTimes(n int) *Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxCalls = n
+	return c
+}
+
+// DoFn returns the function registered via Do or DoAndReturn, or nil if
+// none was registered. Generated code type-asserts this to the mocked
+// method's signature before calling it.
+func (c *Call) DoFn() interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.doFn
+}
+
+// Returns returns the values registered via Return.
+func (c *Call) Returns() []interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.returns
+}
+
+func (c *Call) matches(args []interface{}) bool {
+	if len(c.matchers) != len(args) {
+		return false
+	}
+	for i, m := range c.matchers {
+		if !m.Matches(args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Call) exhausted() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.maxCalls >= 0 && c.numCalls >= c.maxCalls
+}
+
+func (c *Call) satisfied() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.numCalls >= c.minCalls
+}
+
+func (c *Call) String() string {
+	strs := make([]string, len(c.matchers))
+	for i, m := range c.matchers {
+		strs[i] = m.String()
+	}
+	return fmt.Sprintf("%s(%s)", c.method, joinStrings(strs))
+}
+
+func joinStrings(strs []string) string {
+	out := ""
+	for i, s := range strs {
+		if i > 0 {
+			out += ", "
+		}
+		out += s
+	}
+	return out
+}
+
+// callSet is the FIFO list of expectations recorded against a single
+// method name.
+type callSet struct {
+	mu    sync.Mutex
+	calls []*Call
+}
+
+func (s *callSet) add(c *Call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, c)
+}
+
+// findMatch returns the first non-exhausted call whose matchers accept
+// args, marking it as invoked once more.
+func (s *callSet) findMatch(args []interface{}) *Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.calls {
+		if c.exhausted() {
+			continue
+		}
+		if c.matches(args) {
+			c.mu.Lock()
+			c.numCalls++
+			c.mu.Unlock()
+			return c
+		}
+	}
+	return nil
+}
+
+func (s *callSet) verifyExhausted(t TestReporter, method string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.calls {
+		if !c.satisfied() {
+			t.Errorf("moq: expected %s to be called at least %d time(s), got %d", method, c.minCalls, c.numCalls)
+		}
+	}
+}
+
+// Controller owns the expectations recorded through a mock's EXPECT()
+// recorder and matches them against the arguments of each real call.
+// Mocks generated with moq's WithExpectations(true) option hold one
+// Controller and forward every call to it when the mock was built with
+// its generated New constructor.
+type Controller struct {
+	t TestReporter
+
+	mu    sync.Mutex
+	calls map[string]*callSet
+}
+
+// NewController makes a Controller that reports failures to t.
+func NewController(t TestReporter) *Controller {
+	return &Controller{t: t, calls: make(map[string]*callSet)}
+}
+
+func (c *Controller) setFor(method string) *callSet {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	set, ok := c.calls[method]
+	if !ok {
+		set = &callSet{}
+		c.calls[method] = set
+	}
+	return set
+}
+
+// RecordCall is invoked by a generated EXPECT().Method(...) recorder to
+// register a new expectation.
+func (c *Controller) RecordCall(method string, matchers ...Matcher) *Call {
+	call := NewCall(method, matchers...)
+	c.setFor(method).add(call)
+	return call
+}
+
+// Call is invoked by a generated mock method to find the expectation that
+// matches the actual arguments. It reports a fatal error via the
+// controller's TestReporter, and returns nil, if no expectation matches.
+func (c *Controller) Call(method string, args ...interface{}) *Call {
+	call := c.setFor(method).findMatch(args)
+	if call == nil {
+		c.t.Fatalf("moq: unexpected call to %s with arguments %v", method, args)
+		return nil
+	}
+	return call
+}
+
+// Finish reports any expectation that did not see its minimum number of
+// calls. Callers typically defer ctrl.Finish() right after construction.
+func (c *Controller) Finish() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for method, set := range c.calls {
+		set.verifyExhausted(c.t, method)
+	}
+}
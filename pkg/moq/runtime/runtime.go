@@ -0,0 +1,106 @@
+// Package runtime holds the types that generated mocks depend on at
+// runtime when moq is invoked with WithExpectations(true) or
+// WithCallLog(true). Unlike the moq
+// package itself, which only runs at generation time, this package is
+// imported by the generated code, so it is kept free of go/ast, go/types
+// and other generator-only dependencies.
+package runtime
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TestReporter is the subset of *testing.T the generated controllers need
+// in order to report unsatisfied or unexpected calls.
+type TestReporter interface {
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+// Matcher decides whether an actual call argument satisfies an
+// expectation set up via EXPECT().
+type Matcher interface {
+	Matches(x interface{}) bool
+	String() string
+}
+
+// MatcherFunc lets a plain function act as a Matcher, the same way
+// http.HandlerFunc lets a plain function act as an http.Handler.
+type MatcherFunc func(x interface{}) bool
+
+// Matches calls f.
+func (f MatcherFunc) Matches(x interface{}) bool { return f(x) }
+
+// String returns a generic description, since a MatcherFunc has no name
+// of its own.
+func (f MatcherFunc) String() string { return "MatcherFunc" }
+
+type eqMatcher struct{ want interface{} }
+
+func (m eqMatcher) Matches(x interface{}) bool { return reflect.DeepEqual(x, m.want) }
+func (m eqMatcher) String() string             { return fmt.Sprintf("Eq(%v)", m.want) }
+
+// Eq returns a Matcher that matches when the actual argument equals want,
+// as compared with reflect.DeepEqual. Unlike ==, this doesn't panic when
+// want or the actual argument is a slice, map or func.
+func Eq(want interface{}) Matcher { return eqMatcher{want: want} }
+
+type nilMatcher struct{}
+
+func (nilMatcher) Matches(x interface{}) bool { return x == nil }
+func (nilMatcher) String() string             { return "Nil()" }
+
+// Nil returns a Matcher that matches a nil argument.
+func Nil() Matcher { return nilMatcher{} }
+
+type anyMatcher struct{}
+
+func (anyMatcher) Matches(interface{}) bool { return true }
+func (anyMatcher) String() string           { return "Any()" }
+
+// Any returns a Matcher that matches any argument.
+func Any() Matcher { return anyMatcher{} }
+
+type assignableMatcher struct{ want interface{} }
+
+func (m assignableMatcher) Matches(x interface{}) bool {
+	if x == nil || m.want == nil {
+		return false
+	}
+	return reflect.TypeOf(x).AssignableTo(reflect.TypeOf(m.want))
+}
+
+func (m assignableMatcher) String() string {
+	return fmt.Sprintf("AssignableToTypeOf(%T)", m.want)
+}
+
+// AssignableToTypeOf returns a Matcher that matches any argument whose
+// type is assignable to the type of want.
+func AssignableToTypeOf(want interface{}) Matcher { return assignableMatcher{want: want} }
+
+type notMatcher struct{ m Matcher }
+
+func (m notMatcher) Matches(x interface{}) bool { return !m.m.Matches(x) }
+func (m notMatcher) String() string             { return fmt.Sprintf("Not(%s)", m.m) }
+
+// Not returns a Matcher that matches when m does not.
+func Not(m Matcher) Matcher { return notMatcher{m: m} }
+
+type lenMatcher struct{ n int }
+
+func (m lenMatcher) Matches(x interface{}) bool {
+	v := reflect.ValueOf(x)
+	switch v.Kind() {
+	case reflect.Array, reflect.Slice, reflect.Map, reflect.String, reflect.Chan:
+		return v.Len() == m.n
+	default:
+		return false
+	}
+}
+
+func (m lenMatcher) String() string { return fmt.Sprintf("Len(%d)", m.n) }
+
+// Len returns a Matcher that matches any array, slice, map, string or
+// channel argument with length n.
+func Len(n int) Matcher { return lenMatcher{n: n} }
@@ -0,0 +1,28 @@
+package runtime
+
+import "testing"
+
+func TestEqMatchesComparableValues(t *testing.T) {
+	m := Eq(42)
+	if !m.Matches(42) {
+		t.Errorf("Eq(42).Matches(42) = false, want true")
+	}
+	if m.Matches(43) {
+		t.Errorf("Eq(42).Matches(43) = true, want false")
+	}
+}
+
+func TestEqDoesNotPanicOnUncomparableTypes(t *testing.T) {
+	m := Eq([]byte("x"))
+	if !m.Matches([]byte("x")) {
+		t.Errorf("Eq([]byte(\"x\")).Matches([]byte(\"x\")) = false, want true")
+	}
+	if m.Matches([]byte("y")) {
+		t.Errorf("Eq([]byte(\"x\")).Matches([]byte(\"y\")) = true, want false")
+	}
+
+	mapMatcher := Eq(map[string]int{"a": 1})
+	if !mapMatcher.Matches(map[string]int{"a": 1}) {
+		t.Errorf("Eq(map).Matches(equal map) = false, want true")
+	}
+}
@@ -0,0 +1,7 @@
+package barer
+
+import "time"
+
+type Barer interface {
+	Bar(d time.Duration) error
+}
@@ -0,0 +1,9 @@
+//go:build linux || darwin
+
+package buildtag
+
+// Pinger is a trivial interface used to check that MockToFile carries a
+// source package's build constraint over to the generated mock.
+type Pinger interface {
+	Ping() error
+}
@@ -0,0 +1,8 @@
+package base
+
+// Reader is embedded, transitively, by interfaces in sibling packages,
+// to exercise flattening an embedded interface across package
+// boundaries.
+type Reader interface {
+	Read(p []byte) (n int, err error)
+}
@@ -0,0 +1,18 @@
+package conflict
+
+// First and Second both declare Do, with different signatures, so
+// embedding both in Conflicting is invalid - exercising
+// flattenInterfaceMethods' error path for embedded interfaces that
+// disagree on a method's signature.
+type First interface {
+	Do() error
+}
+
+type Second interface {
+	Do(n int) error
+}
+
+type Conflicting interface {
+	First
+	Second
+}
@@ -0,0 +1,10 @@
+package mid
+
+import "github.com/mkfsn/moq/pkg/moq/testdata/embedded/base"
+
+// ReadCloser embeds base.Reader, from another package, alongside its
+// own method.
+type ReadCloser interface {
+	base.Reader
+	Close() error
+}
@@ -0,0 +1,11 @@
+package outer
+
+import "github.com/mkfsn/moq/pkg/moq/testdata/embedded/mid"
+
+// Thing embeds mid.ReadCloser, which itself embeds base.Reader, so
+// mocking Thing exercises flattening an embedded interface transitively
+// across two package boundaries.
+type Thing interface {
+	mid.ReadCloser
+	Do() error
+}
@@ -0,0 +1,7 @@
+package fooer
+
+import "net/http"
+
+type Fooer interface {
+	Foo(r *http.Request) error
+}
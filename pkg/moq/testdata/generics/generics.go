@@ -0,0 +1,16 @@
+package generics
+
+// Number is a union constraint over the built-in numeric types Sum
+// accepts.
+type Number interface {
+	~int | ~float64
+}
+
+// Store is a generic interface with three type parameters - a
+// comparable key, an unconstrained value, and a Number - and a variadic
+// method, exercised by Mock's generics support.
+type Store[K comparable, V any, N Number] interface {
+	Get(key K) (V, bool)
+	Set(key K, val V)
+	Sum(nums ...N) N
+}